@@ -1,15 +1,27 @@
 package main
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"log/slog"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
-	"strconv"
+	"sort"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
+
+	"github.com/papaert-cloud/BeaconAgileHub-security-CI-CDpipelines/pkg/attestation"
+	"github.com/papaert-cloud/BeaconAgileHub-security-CI-CDpipelines/pkg/remediation"
+	"golang.org/x/sync/errgroup"
 )
 
 // SecurityScanResult represents the consolidated security scan results
@@ -22,15 +34,89 @@ type SecurityScanResult struct {
 	VulnerabilityResults VulnerabilityResults `json:"vulnerability_results"`
 	SecurityFindings SecurityFindings       `json:"security_findings"`
 	ComplianceStatus ComplianceStatus       `json:"compliance_status"`
+	RemediationReport *remediation.Report   `json:"remediation_report,omitempty"`
 	Recommendations  []string               `json:"recommendations"`
 }
 
 // VulnerabilityResults contains vulnerability scan results from multiple tools
 type VulnerabilityResults struct {
-	GrypeResults GrypeScanResult `json:"grype_results"`
-	SnykResults  SnykScanResult  `json:"snyk_results"`
-	TrivyResults TrivyScanResult `json:"trivy_results"`
-	Summary      VulnSummary     `json:"summary"`
+	GrypeResults              GrypeScanResult `json:"grype_results"`
+	SnykResults               SnykScanResult  `json:"snyk_results"`
+	TrivyResults              TrivyScanResult `json:"trivy_results"`
+	SuppressedVulnerabilities []Match         `json:"suppressed_vulnerabilities,omitempty"`
+	// MergedFindings is populated only when ScannerConfig.ByCVE is set.
+	MergedFindings []MergedFinding `json:"merged_findings,omitempty"`
+	Summary        VulnSummary     `json:"summary"`
+}
+
+// Match is a normalized vulnerability match used for VEX filtering, independent
+// of which scanner (Grype/Snyk/Trivy) originally produced it.
+type Match struct {
+	VulnerabilityID string `json:"vulnerability_id"`
+	Package         string `json:"package"`
+	Version         string `json:"version"`
+	Severity        string `json:"severity"`
+	Source          string `json:"source"`
+	// Status is the VEX status applied to this match, e.g. "affected",
+	// "not_affected", "fixed", "under_investigation". Empty when no VEX
+	// statement matched the (vuln_id, package, version) tuple.
+	Status        string `json:"status,omitempty"`
+	Justification string `json:"justification,omitempty"`
+}
+
+// VEX statuses, following the vulnerability-status taxonomy shared by
+// OpenVEX/CSAF-VEX and Trivy.
+const (
+	VEXStatusNotAffected       = "not_affected"
+	VEXStatusAffected          = "affected"
+	VEXStatusFixed             = "fixed"
+	VEXStatusUnderInvestigation = "under_investigation"
+	VEXStatusWillNotFix        = "will_not_fix"
+	VEXStatusFixDeferred       = "fix_deferred"
+	VEXStatusEndOfLife         = "end_of_life"
+	VEXStatusUnknown           = "unknown"
+)
+
+// VEXDocument is a minimal OpenVEX/CSAF-VEX document: a set of statements,
+// each asserting a status for one or more vulnerabilities against one or
+// more products (packages).
+type VEXDocument struct {
+	Statements []VEXStatement `json:"statements"`
+}
+
+// VEXStatement asserts a VEX status for a vulnerability against a set of
+// products, optionally scoped to specific versions via subcomponents.
+type VEXStatement struct {
+	Vulnerability VEXVulnerability `json:"vulnerability"`
+	Products      []VEXProduct     `json:"products"`
+	Status        string           `json:"status"`
+	Justification string           `json:"justification"`
+}
+
+type VEXVulnerability struct {
+	ID string `json:"@id"`
+	// Name covers documents that key the vulnerability by CVE/GHSA name
+	// instead of an @id URI.
+	Name string `json:"name"`
+}
+
+type VEXProduct struct {
+	ID             string   `json:"@id"`
+	Identifiers    map[string]string `json:"identifiers"`
+	Subcomponents  []VEXSubcomponent `json:"subcomponents"`
+}
+
+type VEXSubcomponent struct {
+	ID string `json:"@id"`
+}
+
+// vexID returns the statement's vulnerability identifier, preferring the
+// @id form and falling back to name.
+func (s VEXStatement) vexID() string {
+	if s.Vulnerability.ID != "" {
+		return s.Vulnerability.ID
+	}
+	return s.Vulnerability.Name
 }
 
 // GrypeScanResult represents Grype vulnerability scan results
@@ -45,10 +131,27 @@ type GrypeMatch struct {
 }
 
 type GrypeVulnerability struct {
-	ID          string   `json:"id"`
-	Severity    string   `json:"severity"`
-	Description string   `json:"description"`
-	URLs        []string `json:"urls"`
+	ID          string     `json:"id"`
+	Severity    string     `json:"severity"`
+	Description string     `json:"description"`
+	URLs        []string   `json:"urls"`
+	Fix         GrypeFix   `json:"fix"`
+	// RelatedVulnerabilities carries the NVD/OSV aliases (e.g. a GHSA ID's
+	// underlying CVE) Grype embeds alongside a match.
+	RelatedVulnerabilities []GrypeRelatedVulnerability `json:"relatedVulnerabilities"`
+}
+
+// GrypeRelatedVulnerability is an alias Grype associates with a match, used
+// to collapse duplicate findings reported under different IDs.
+type GrypeRelatedVulnerability struct {
+	ID string `json:"id"`
+}
+
+// GrypeFix describes the fixed-in versions Grype found for a vulnerability,
+// if any.
+type GrypeFix struct {
+	Versions []string `json:"versions"`
+	State    string   `json:"state"`
 }
 
 type GrypeArtifact struct {
@@ -69,10 +172,19 @@ type SnykScanResult struct {
 }
 
 type SnykVulnerability struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	Severity string `json:"severity"`
-	Package  string `json:"package"`
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Severity    string          `json:"severity"`
+	Package     string          `json:"package"`
+	Version     string          `json:"version"`
+	Identifiers SnykIdentifiers `json:"identifiers"`
+}
+
+// SnykIdentifiers carries the CVE/GHSA aliases Snyk associates with its own
+// SNYK-* vulnerability ID.
+type SnykIdentifiers struct {
+	CVE  []string `json:"CVE"`
+	GHSA []string `json:"GHSA"`
 }
 
 type SnykSummary struct {
@@ -94,21 +206,47 @@ type TrivyResult struct {
 }
 
 type TrivyVulnerability struct {
-	VulnerabilityID string `json:"VulnerabilityID"`
-	Severity        string `json:"Severity"`
-	Title           string `json:"Title"`
-	PkgName         string `json:"PkgName"`
+	VulnerabilityID  string   `json:"VulnerabilityID"`
+	Severity         string   `json:"Severity"`
+	Title            string   `json:"Title"`
+	PkgName          string   `json:"PkgName"`
+	InstalledVersion string   `json:"InstalledVersion"`
+	FixedVersion     string   `json:"FixedVersion"`
+	VendorIDs        []string `json:"VendorIDs"`
 }
 
 // VulnSummary provides aggregated vulnerability statistics
 type VulnSummary struct {
-	TotalVulnerabilities int `json:"total_vulnerabilities"`
-	Critical             int `json:"critical"`
-	High                 int `json:"high"`
-	Medium               int `json:"medium"`
-	Low                  int `json:"low"`
-	Info                 int `json:"info"`
+	TotalVulnerabilities int  `json:"total_vulnerabilities"`
+	Critical             int  `json:"critical"`
+	High                 int  `json:"high"`
+	Medium               int  `json:"medium"`
+	Low                  int  `json:"low"`
+	Info                 int  `json:"info"`
 	BlockDeployment      bool `json:"block_deployment"`
+
+	// Per-VEX-status counters. Affected and UnderInvestigation are also
+	// reflected in the severity counters above; NotAffected and Fixed are
+	// suppressed out of the active counts entirely.
+	Affected           int `json:"affected"`
+	NotAffected        int `json:"not_affected"`
+	Fixed              int `json:"fixed"`
+	UnderInvestigation int `json:"under_investigation"`
+
+	// UniqueCVEs is populated only when ScannerConfig.ByCVE is set: the
+	// number of distinct CVEs after collapsing cross-scanner aliases.
+	UniqueCVEs int `json:"unique_cves,omitempty"`
+}
+
+// MergedFinding is a single CVE (or GHSA/vendor ID, when no CVE exists)
+// with the evidence trail from every scanner that independently reported
+// it, collapsed via alias resolution.
+type MergedFinding struct {
+	CVEID            string   `json:"cve_id"`
+	Aliases          []string `json:"aliases"`
+	Sources          []string `json:"sources"`
+	MaxSeverity      string   `json:"max_severity"`
+	AffectedPackages []string `json:"affected_packages"`
 }
 
 // SecurityFindings contains static analysis and secret scan results
@@ -124,8 +262,29 @@ type ComplianceStatus struct {
 	SLSALevel    int  `json:"slsa_level"`
 	SSDFCompliant bool `json:"ssdf_compliant"`
 	CISCompliant  bool `json:"cis_compliant"`
+	// KubernetesHardened reflects CIS Kubernetes Benchmark results for a
+	// ScanTargetCluster scan; unset (false) for directory scans.
+	KubernetesHardened bool `json:"kubernetes_hardened"`
+	// ProvenanceSigned reports whether a SLSA provenance statement for
+	// this scan's artifacts was generated and signed with cosign.
+	ProvenanceSigned bool `json:"provenance_signed"`
+}
+
+// ScanTarget identifies what a SecurityScanner run scans: a filesystem
+// working directory, or a live Kubernetes cluster/namespace.
+type ScanTarget struct {
+	Kind       string `json:"kind"`
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	Context    string `json:"context,omitempty"`
+	Namespace  string `json:"namespace,omitempty"`
 }
 
+// Supported ScanTarget.Kind values.
+const (
+	ScanTargetDirectory = "directory"
+	ScanTargetCluster    = "cluster"
+)
+
 // SecurityScanner orchestrates comprehensive security scanning
 type SecurityScanner struct {
 	workDir     string
@@ -134,6 +293,9 @@ type SecurityScanner struct {
 	commitSHA   string
 	scanID      string
 	config      ScannerConfig
+	target      ScanTarget
+	startedAt   time.Time
+	provenanceSigned bool
 }
 
 // ScannerConfig holds configuration for the security scanner
@@ -145,6 +307,26 @@ type ScannerConfig struct {
 	SeverityThreshold string `json:"severity_threshold"`
 	BlockOnHigh       bool   `json:"block_on_high"`
 	OutputFormat      string `json:"output_format"`
+
+	// VEXDocumentPaths lists OpenVEX/CSAF-VEX documents used to suppress
+	// findings that are not exploitable in this product's context.
+	VEXDocumentPaths []string `json:"vex_document_paths"`
+
+	// EnableRemediation turns on generation of remediation-report.json; set
+	// via the --fix CLI flag. ApplyRemediation additionally writes the
+	// generated patches back to the manifest files.
+	EnableRemediation bool `json:"enable_remediation"`
+	ApplyRemediation  bool `json:"apply_remediation"`
+
+	// ByCVE turns on cross-scanner alias resolution, so the same CVE found
+	// by multiple tools is reported once; set via the --by-cve CLI flag.
+	ByCVE bool `json:"by_cve"`
+
+	// MaxConcurrency caps how many scanners run at once in the concurrent
+	// pipeline; 0 means "one per enabled scanner".
+	MaxConcurrency int `json:"max_concurrency"`
+	// ToolTimeoutSeconds bounds each scanner's run; 0 defaults to 5 minutes.
+	ToolTimeoutSeconds int `json:"tool_timeout_seconds"`
 }
 
 // NewSecurityScanner creates a new security scanner instance
@@ -157,6 +339,7 @@ func NewSecurityScanner(workDir, outputDir, repository, commitSHA string) *Secur
 		repository: repository,
 		commitSHA:  commitSHA,
 		scanID:     scanID,
+		target:     ScanTarget{Kind: ScanTargetDirectory},
 		config: ScannerConfig{
 			EnableGrype:       true,
 			EnableSnyk:        true,
@@ -169,10 +352,25 @@ func NewSecurityScanner(workDir, outputDir, repository, commitSHA string) *Secur
 	}
 }
 
+// NewKubernetesSecurityScanner creates a SecurityScanner targeting a live
+// Kubernetes cluster (or namespace within it) instead of a working
+// directory.
+func NewKubernetesSecurityScanner(kubeconfig, kubeContext, namespace, outputDir, repository, commitSHA string) *SecurityScanner {
+	scanner := NewSecurityScanner("", outputDir, repository, commitSHA)
+	scanner.target = ScanTarget{
+		Kind:       ScanTargetCluster,
+		Kubeconfig: kubeconfig,
+		Context:    kubeContext,
+		Namespace:  namespace,
+	}
+	return scanner
+}
+
 // RunComprehensiveScan executes all security scanning tools
-func (s *SecurityScanner) RunComprehensiveScan() (*SecurityScanResult, error) {
+func (s *SecurityScanner) RunComprehensiveScan(ctx context.Context) (*SecurityScanResult, error) {
 	log.Printf("Starting comprehensive security scan for %s@%s", s.repository, s.commitSHA)
-	
+	s.startedAt = time.Now()
+
 	result := &SecurityScanResult{
 		Timestamp:   time.Now(),
 		Repository:  s.repository,
@@ -185,8 +383,16 @@ func (s *SecurityScanner) RunComprehensiveScan() (*SecurityScanResult, error) {
 		return nil, fmt.Errorf("failed to create output directory: %w", err)
 	}
 	
-	// Step 1: Generate SBOM
-	if err := s.generateSBOM(); err != nil {
+	// Step 1: Generate SBOM (or KBOM, when targeting a cluster)
+	if s.target.Kind == ScanTargetCluster {
+		if err := s.generateKBOM(); err != nil {
+			log.Printf("KBOM generation failed: %v", err)
+			result.SBOMGenerated = false
+		} else {
+			result.SBOMGenerated = true
+			log.Println("✅ KBOM generated successfully")
+		}
+	} else if err := s.generateSBOM(); err != nil {
 		log.Printf("SBOM generation failed: %v", err)
 		result.SBOMGenerated = false
 	} else {
@@ -194,35 +400,97 @@ func (s *SecurityScanner) RunComprehensiveScan() (*SecurityScanResult, error) {
 		log.Println("✅ SBOM generated successfully")
 	}
 	
-	// Step 2: Run vulnerability scans
-	vulnResults, err := s.runVulnerabilityScans()
+	// Step 2: Run vulnerability and secret scans concurrently
+	vulnResults, secFindings, err := s.runScansConcurrent(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("vulnerability scanning failed: %w", err)
+		return nil, fmt.Errorf("concurrent scanning failed: %w", err)
 	}
 	result.VulnerabilityResults = *vulnResults
-	
-	// Step 3: Run security analysis
-	secFindings, err := s.runSecurityAnalysis()
-	if err != nil {
-		return nil, fmt.Errorf("security analysis failed: %w", err)
-	}
 	result.SecurityFindings = *secFindings
-	
+
+	// Step 3: Generate remediation patches for fixable findings
+	if s.config.EnableRemediation {
+		remediationReport, err := s.RunRemediation(result)
+		if err != nil {
+			log.Printf("Remediation failed: %v", err)
+		} else {
+			result.RemediationReport = remediationReport
+			log.Printf("Remediation report: %d fixable, %d unfixable", remediationReport.FixableCount, remediationReport.UnfixableCount)
+		}
+	}
+
 	// Step 4: Assess compliance status
 	result.ComplianceStatus = s.assessCompliance(result)
-	
+
 	// Step 5: Generate recommendations
 	result.Recommendations = s.generateRecommendations(result)
-	
+
 	// Step 6: Save results
 	if err := s.saveResults(result); err != nil {
 		return nil, fmt.Errorf("failed to save results: %w", err)
 	}
-	
+
+	// Step 7: Attest and sign the scan artifacts, then reassess compliance
+	// and re-save results if signing succeeded
+	if s.attestResults(result) {
+		result.ComplianceStatus = s.assessCompliance(result)
+		if err := s.saveResults(result); err != nil {
+			return nil, fmt.Errorf("failed to save results after attestation: %w", err)
+		}
+	}
+
 	log.Printf("✅ Security scan completed. Scan ID: %s", s.scanID)
 	return result, nil
 }
 
+// attestResults generates an in-toto/SLSA provenance statement over this
+// scan's artifacts and signs it with Sigstore cosign in keyless mode. It
+// returns whether signing succeeded, so the caller can bump SLSALevel and
+// persist the updated compliance status.
+//
+// security-scan-results.json is deliberately excluded from the attested set:
+// it embeds ComplianceStatus, which is itself derived from whether signing
+// succeeds, so it gets rewritten after this statement is signed and would
+// never match the digest attested here. Only the append-only scanner
+// outputs (SBOM/grype) are attested.
+func (s *SecurityScanner) attestResults(result *SecurityScanResult) bool {
+	artifactNames := []string{"sbom-cyclonedx.json", "grype-results.json"}
+	if s.target.Kind == ScanTargetCluster {
+		artifactNames[0] = "sbom-kbom-cyclonedx.json"
+	}
+
+	var artifactPaths []string
+	for _, name := range artifactNames {
+		path := filepath.Join(s.outputDir, name)
+		if _, err := os.Stat(path); err == nil {
+			artifactPaths = append(artifactPaths, path)
+		}
+	}
+
+	attestor := attestation.NewAttestor(s.outputDir)
+
+	statement, err := attestor.GenerateStatement(artifactPaths, s.repository, s.commitSHA, s.startedAt, time.Now())
+	if err != nil {
+		log.Printf("Provenance generation failed: %v", err)
+		return false
+	}
+
+	statementPath, err := attestor.Save(statement)
+	if err != nil {
+		log.Printf("Failed to save provenance statement: %v", err)
+		return false
+	}
+
+	if err := attestor.Sign(statementPath); err != nil {
+		log.Printf("Provenance signing failed: %v", err)
+		return false
+	}
+
+	log.Println("✅ Provenance statement signed with cosign")
+	s.provenanceSigned = true
+	return true
+}
+
 // generateSBOM creates Software Bill of Materials using Syft
 func (s *SecurityScanner) generateSBOM() error {
 	log.Println("Generating SBOM with Syft...")
@@ -248,173 +516,945 @@ func (s *SecurityScanner) generateSBOM() error {
 	return nil
 }
 
-// runVulnerabilityScans executes multiple vulnerability scanning tools
-func (s *SecurityScanner) runVulnerabilityScans() (*VulnerabilityResults, error) {
-	log.Println("Running vulnerability scans...")
-	
-	results := &VulnerabilityResults{}
-	
-	// Run Grype scan
+// generateKBOM creates a CycloneDX Kubernetes BOM describing the target
+// cluster's control-plane components, node OS/kernel/container-runtime
+// versions, and workload images, using trivy's k8s BOM support.
+func (s *SecurityScanner) generateKBOM() error {
+	log.Println("Generating KBOM for cluster scan...")
+
+	outputPath := filepath.Join(s.outputDir, "sbom-kbom-cyclonedx.json")
+
+	args := []string{"k8s", "--report", "all", "--format", "cyclonedx", "--output", outputPath}
+	if s.target.Kubeconfig != "" {
+		args = append(args, "--kubeconfig", s.target.Kubeconfig)
+	}
+	if s.target.Context != "" {
+		args = append(args, "--context", s.target.Context)
+	}
+	if s.target.Namespace != "" {
+		args = append(args, "--namespace", s.target.Namespace)
+	} else {
+		args = append(args, "--all-namespaces")
+	}
+	args = append(args, "cluster")
+
+	cmd := exec.Command("trivy", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("trivy k8s KBOM generation failed: %w\nOutput: %s", err, string(output))
+	}
+
+	log.Printf("Generated KBOM: %s", filepath.Base(outputPath))
+	return nil
+}
+
+// assessKubernetesHardening runs kube-bench against the target cluster and
+// reports whether it passes the CIS Kubernetes Benchmark with no failures.
+func (s *SecurityScanner) assessKubernetesHardening() bool {
+	outputPath := filepath.Join(s.outputDir, "kube-bench-results.json")
+
+	args := []string{"run", "--targets", "master,node,etcd,policies", "--json"}
+	if s.target.Kubeconfig != "" {
+		args = append(args, "--kubeconfig", s.target.Kubeconfig)
+	}
+
+	cmd := exec.Command("kube-bench", args...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		log.Printf("kube-bench run failed: %v", err)
+		return false
+	}
+
+	if err := os.WriteFile(outputPath, output, 0644); err != nil {
+		log.Printf("failed to write kube-bench results: %v", err)
+	}
+
+	return !strings.Contains(string(output), "\"FAIL\"")
+}
+
+// RawResult is the unprocessed output of a single scanner run, as produced
+// by the concurrent pipeline in runScansConcurrent.
+type RawResult struct {
+	Tool       string `json:"tool"`
+	Output     []byte `json:"-"`
+	Cached     bool   `json:"cached"`
+	DurationMS int64  `json:"duration_ms"`
+}
+
+// Scanner is implemented by each vulnerability tool so new scanners
+// (Semgrep, Checkov, ...) can be plugged into the concurrent pipeline
+// without changing the orchestrator.
+type Scanner interface {
+	Name() string
+	Scan(ctx context.Context, sbomPath string) (RawResult, error)
+}
+
+// runScansConcurrent fans out every enabled Scanner plus the Gitleaks
+// secret scan as goroutines bounded by ScannerConfig.MaxConcurrency, each
+// under its own per-tool timeout, and cancellable via ctx.
+func (s *SecurityScanner) runScansConcurrent(ctx context.Context) (*VulnerabilityResults, *SecurityFindings, error) {
+	slog.Info("Running vulnerability and secret scans concurrently")
+
+	var scanners []Scanner
 	if s.config.EnableGrype {
-		grypeResults, err := s.runGrypeScan()
-		if err != nil {
-			log.Printf("Grype scan failed: %v", err)
-		} else {
-			results.GrypeResults = *grypeResults
-			log.Println("✅ Grype scan completed")
+		scanners = append(scanners, grypeScanner{s})
+	}
+	// Snyk only scans a filesystem working directory; it has no KBOM/SBOM
+	// mode, so it's skipped entirely for cluster scans rather than run
+	// against an unset workDir.
+	if s.config.EnableSnyk && os.Getenv("SNYK_TOKEN") != "" && s.target.Kind == ScanTargetDirectory {
+		scanners = append(scanners, snykScanner{s})
+	}
+	if s.config.EnableTrivy {
+		scanners = append(scanners, trivyScanner{s})
+	}
+
+	sbomFile := "sbom-cyclonedx.json"
+	if s.target.Kind == ScanTargetCluster {
+		sbomFile = "sbom-kbom-cyclonedx.json"
+	}
+	sbomPath := filepath.Join(s.outputDir, sbomFile)
+
+	maxConcurrency := s.config.MaxConcurrency
+	if maxConcurrency <= 0 {
+		maxConcurrency = len(scanners) + 1 // +1 for the Gitleaks secret scan
+	}
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(maxConcurrency)
+
+	var mu sync.Mutex
+	rawResults := map[string]RawResult{}
+
+	for _, scanner := range scanners {
+		scanner := scanner
+		group.Go(func() error {
+			raw, err := s.runWithTimeout(groupCtx, scanner.Name(), scanner.Scan, sbomPath)
+			if err != nil {
+				slog.Warn("scanner failed", "tool", scanner.Name(), "error", err)
+				return nil
+			}
+			mu.Lock()
+			rawResults[scanner.Name()] = raw
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	var findings SecurityFindings
+	// Gitleaks scans the working directory, same as Snyk; there's no
+	// secret-scanning equivalent for a cluster's KBOM, so it's skipped for
+	// cluster scans rather than run against an unset workDir.
+	if s.config.EnableSecrets && s.target.Kind == ScanTargetDirectory {
+		group.Go(func() error {
+			toolCtx, cancel := context.WithTimeout(groupCtx, s.toolTimeout())
+			defer cancel()
+
+			start := time.Now()
+			secretsFound, cached, err := s.runSecretScan(toolCtx)
+			if err != nil {
+				slog.Warn("scanner failed", "tool", "gitleaks", "error", err)
+				return nil
+			}
+
+			slog.Info("scanner completed", "tool", "gitleaks", "duration_ms", time.Since(start).Milliseconds(), "cached", cached)
+
+			mu.Lock()
+			findings.SecretsFound = secretsFound
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	if err := group.Wait(); err != nil {
+		return nil, nil, fmt.Errorf("concurrent scan pipeline failed: %w", err)
+	}
+
+	results := &VulnerabilityResults{}
+	if raw, ok := rawResults["grype"]; ok {
+		if err := json.Unmarshal(raw.Output, &results.GrypeResults); err != nil {
+			slog.Warn("failed to parse Grype results", "error", err)
 		}
+		slog.Info("scanner completed", "tool", "grype", "duration_ms", raw.DurationMS, "cached", raw.Cached)
 	}
-	
-	// Run Snyk scan (if token available)
-	if s.config.EnableSnyk && os.Getenv("SNYK_TOKEN") != "" {
-		snykResults, err := s.runSnykScan()
-		if err != nil {
-			log.Printf("Snyk scan failed: %v", err)
-		} else {
-			results.SnykResults = *snykResults
-			log.Println("✅ Snyk scan completed")
+	if raw, ok := rawResults["snyk"]; ok {
+		if err := json.Unmarshal(raw.Output, &results.SnykResults); err != nil {
+			slog.Warn("failed to parse Snyk results", "error", err)
 		}
+		slog.Info("scanner completed", "tool", "snyk", "duration_ms", raw.DurationMS, "cached", raw.Cached)
 	}
-	
-	// Run Trivy scan
-	if s.config.EnableTrivy {
-		trivyResults, err := s.runTrivyScan()
-		if err != nil {
-			log.Printf("Trivy scan failed: %v", err)
-		} else {
-			results.TrivyResults = *trivyResults
-			log.Println("✅ Trivy scan completed")
+	if raw, ok := rawResults["trivy"]; ok {
+		if err := json.Unmarshal(raw.Output, &results.TrivyResults); err != nil {
+			slog.Warn("failed to parse Trivy results", "error", err)
 		}
+		slog.Info("scanner completed", "tool", "trivy", "duration_ms", raw.DurationMS, "cached", raw.Cached)
 	}
-	
-	// Generate summary
-	results.Summary = s.generateVulnerabilitySummary(results)
-	
-	return results, nil
+
+	// Load VEX documents (if configured) and suppress non-exploitable findings
+	vexStatements, err := s.loadVEXStatements()
+	if err != nil {
+		slog.Warn("VEX document loading failed", "error", err)
+	}
+
+	results.Summary = s.generateVulnerabilitySummary(results, vexStatements)
+
+	// Collapse cross-scanner duplicates into a single CVE-oriented view
+	if s.config.ByCVE {
+		results.MergedFindings = mergeFindingsByCVE(results)
+		results.Summary.UniqueCVEs = len(results.MergedFindings)
+	}
+
+	return results, &findings, nil
 }
 
-// runGrypeScan executes Grype vulnerability scanner
-func (s *SecurityScanner) runGrypeScan() (*GrypeScanResult, error) {
-	sbomPath := filepath.Join(s.outputDir, "sbom-cyclonedx.json")
-	outputPath := filepath.Join(s.outputDir, "grype-results.json")
-	
-	cmd := exec.Command("grype", fmt.Sprintf("sbom:%s", sbomPath), "-o", "json", "--file", outputPath)
-	
-	if output, err := cmd.CombinedOutput(); err != nil {
+// runWithTimeout wraps a Scanner's Scan call with the configured per-tool
+// timeout and logs its wall time.
+func (s *SecurityScanner) runWithTimeout(ctx context.Context, name string, scan func(context.Context, string) (RawResult, error), sbomPath string) (RawResult, error) {
+	toolCtx, cancel := context.WithTimeout(ctx, s.toolTimeout())
+	defer cancel()
+
+	start := time.Now()
+	raw, err := scan(toolCtx, sbomPath)
+	if err != nil {
+		return RawResult{}, err
+	}
+	raw.DurationMS = time.Since(start).Milliseconds()
+	return raw, nil
+}
+
+// toolTimeout returns the configured per-tool timeout, defaulting to 5
+// minutes when unset.
+func (s *SecurityScanner) toolTimeout() time.Duration {
+	if s.config.ToolTimeoutSeconds > 0 {
+		return time.Duration(s.config.ToolTimeoutSeconds) * time.Second
+	}
+	return 5 * time.Minute
+}
+
+// grypeScanner adapts Grype to the Scanner interface, caching results by
+// the sha256 of the SBOM it scans.
+type grypeScanner struct{ s *SecurityScanner }
+
+func (g grypeScanner) Name() string { return "grype" }
+
+func (g grypeScanner) Scan(ctx context.Context, sbomPath string) (RawResult, error) {
+	if key, ok := cacheKeyForFile(sbomPath); ok {
+		if cached, hit := loadCache("grype", key); hit {
+			return RawResult{Tool: "grype", Output: cached, Cached: true}, nil
+		}
+	}
+
+	outputPath := filepath.Join(g.s.outputDir, "grype-results.json")
+	cmd := exec.CommandContext(ctx, "grype", fmt.Sprintf("sbom:%s", sbomPath), "-o", "json", "--file", outputPath)
+
+	if output, err := cmd.CombinedOutput(); err != nil && ctx.Err() != nil {
+		return RawResult{}, fmt.Errorf("grype scan canceled: %w", ctx.Err())
+	} else if err != nil {
 		// Grype returns non-zero exit code when vulnerabilities are found
 		log.Printf("Grype output: %s", string(output))
 	}
-	
-	// Read and parse results
+
 	data, err := os.ReadFile(outputPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Grype results: %w", err)
+		return RawResult{}, fmt.Errorf("failed to read Grype results: %w", err)
 	}
-	
-	var results GrypeScanResult
-	if err := json.Unmarshal(data, &results); err != nil {
-		return nil, fmt.Errorf("failed to parse Grype results: %w", err)
+
+	if key, ok := cacheKeyForFile(sbomPath); ok {
+		storeCache("grype", key, data)
 	}
-	
-	return &results, nil
+
+	return RawResult{Tool: "grype", Output: data}, nil
 }
 
-// runSnykScan executes Snyk vulnerability scanner
-func (s *SecurityScanner) runSnykScan() (*SnykScanResult, error) {
-	outputPath := filepath.Join(s.outputDir, "snyk-results.json")
-	
-	cmd := exec.Command("snyk", "test", "--json", "--file", outputPath)
-	cmd.Dir = s.workDir
-	
+// snykScanner adapts Snyk to the Scanner interface. Snyk scans the working
+// directory rather than the SBOM, so sbomPath is unused; its cache key is
+// the working directory's file tree hash.
+type snykScanner struct{ s *SecurityScanner }
+
+func (n snykScanner) Name() string { return "snyk" }
+
+func (n snykScanner) Scan(ctx context.Context, _ string) (RawResult, error) {
+	if key, ok := cacheKeyForTree(n.s.workDir); ok {
+		if cached, hit := loadCache("snyk", key); hit {
+			return RawResult{Tool: "snyk", Output: cached, Cached: true}, nil
+		}
+	}
+
+	outputPath := filepath.Join(n.s.outputDir, "snyk-results.json")
+	cmd := exec.CommandContext(ctx, "snyk", "test", "--json", "--file", outputPath)
+	cmd.Dir = n.s.workDir
+
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		// Snyk returns non-zero exit code when vulnerabilities are found
-		log.Printf("Snyk output: %s", string(output))
+		if ctx.Err() != nil {
+			return RawResult{}, fmt.Errorf("snyk scan canceled: %w", ctx.Err())
+		}
+		// Snyk returns exit code 1 when vulnerabilities are found; its JSON
+		// report is on stdout regardless. Any other exit code (missing
+		// binary, auth failure, network error) is a genuine scan failure
+		// and must not be cached as a successful result.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return RawResult{}, fmt.Errorf("snyk scan failed: %w\nOutput: %s", err, string(output))
+		}
 	}
-	
-	// Parse Snyk output (it outputs JSON to stdout)
-	var results SnykScanResult
-	if err := json.Unmarshal(output, &results); err != nil {
-		return nil, fmt.Errorf("failed to parse Snyk results: %w", err)
+
+	if key, ok := cacheKeyForTree(n.s.workDir); ok {
+		storeCache("snyk", key, output)
 	}
-	
-	return &results, nil
+
+	return RawResult{Tool: "snyk", Output: output}, nil
 }
 
-// runTrivyScan executes Trivy vulnerability scanner
-func (s *SecurityScanner) runTrivyScan() (*TrivyScanResult, error) {
-	outputPath := filepath.Join(s.outputDir, "trivy-results.json")
-	
-	cmd := exec.Command("trivy", "fs", "--format", "json", "--output", outputPath, s.workDir)
-	
+// trivyScanner adapts Trivy to the Scanner interface, caching results by
+// the working directory's file tree hash for directory scans and by the
+// KBOM's sha256 for cluster scans.
+type trivyScanner struct{ s *SecurityScanner }
+
+func (t trivyScanner) Name() string { return "trivy" }
+
+func (t trivyScanner) Scan(ctx context.Context, sbomPath string) (RawResult, error) {
+	if t.s.target.Kind == ScanTargetCluster {
+		return t.scanSBOM(ctx, sbomPath)
+	}
+	return t.scanFilesystem(ctx)
+}
+
+// scanFilesystem runs Trivy against the working directory, for directory
+// scans, caching results by the working directory's file tree hash.
+func (t trivyScanner) scanFilesystem(ctx context.Context) (RawResult, error) {
+	if key, ok := cacheKeyForTree(t.s.workDir); ok {
+		if cached, hit := loadCache("trivy", key); hit {
+			return RawResult{Tool: "trivy", Output: cached, Cached: true}, nil
+		}
+	}
+
+	outputPath := filepath.Join(t.s.outputDir, "trivy-results.json")
+	cmd := exec.CommandContext(ctx, "trivy", "fs", "--format", "json", "--output", outputPath, t.s.workDir)
+
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return nil, fmt.Errorf("trivy scan failed: %w\nOutput: %s", err, string(output))
+		if ctx.Err() != nil {
+			return RawResult{}, fmt.Errorf("trivy scan canceled: %w", ctx.Err())
+		}
+		return RawResult{}, fmt.Errorf("trivy scan failed: %w\nOutput: %s", err, string(output))
 	}
-	
-	// Read and parse results
+
 	data, err := os.ReadFile(outputPath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read Trivy results: %w", err)
+		return RawResult{}, fmt.Errorf("failed to read Trivy results: %w", err)
 	}
-	
-	var results TrivyScanResult
-	if err := json.Unmarshal(data, &results); err != nil {
-		return nil, fmt.Errorf("failed to parse Trivy results: %w", err)
+
+	if key, ok := cacheKeyForTree(t.s.workDir); ok {
+		storeCache("trivy", key, data)
 	}
-	
-	return &results, nil
+
+	return RawResult{Tool: "trivy", Output: data}, nil
 }
 
-// runSecurityAnalysis performs static analysis and secret scanning
-func (s *SecurityScanner) runSecurityAnalysis() (*SecurityFindings, error) {
-	log.Println("Running security analysis...")
-	
-	findings := &SecurityFindings{}
-	
-	// Run secret scanning with Gitleaks
-	if s.config.EnableSecrets {
-		secretsFound, err := s.runSecretScan()
+// scanSBOM runs Trivy against the cluster KBOM, for cluster scans, matching
+// control-plane components against known CVEs. Caching keys off the KBOM
+// file's sha256, same as grypeScanner.
+func (t trivyScanner) scanSBOM(ctx context.Context, sbomPath string) (RawResult, error) {
+	if key, ok := cacheKeyForFile(sbomPath); ok {
+		if cached, hit := loadCache("trivy", key); hit {
+			return RawResult{Tool: "trivy", Output: cached, Cached: true}, nil
+		}
+	}
+
+	outputPath := filepath.Join(t.s.outputDir, "trivy-results.json")
+	cmd := exec.CommandContext(ctx, "trivy", "sbom", "--format", "json", "--output", outputPath, sbomPath)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return RawResult{}, fmt.Errorf("trivy scan canceled: %w", ctx.Err())
+		}
+		return RawResult{}, fmt.Errorf("trivy scan failed: %w\nOutput: %s", err, string(output))
+	}
+
+	data, err := os.ReadFile(outputPath)
+	if err != nil {
+		return RawResult{}, fmt.Errorf("failed to read Trivy results: %w", err)
+	}
+
+	if key, ok := cacheKeyForFile(sbomPath); ok {
+		storeCache("trivy", key, data)
+	}
+
+	return RawResult{Tool: "trivy", Output: data}, nil
+}
+
+// rawFinding is a scanner-agnostic view of a single vulnerability match,
+// used as input to both VEX suppression and mergeFindingsByCVE's alias
+// resolution.
+type rawFinding struct {
+	id       string
+	aliases  []string
+	source   string
+	severity string
+	pkg      string
+	version  string
+}
+
+// collectRawFindings builds a scanner-agnostic view of every finding across
+// Grype, Snyk and Trivy, used as input to both VEX suppression
+// (generateVulnerabilitySummary) and CVE alias resolution (mergeFindingsByCVE).
+func collectRawFindings(results *VulnerabilityResults) []rawFinding {
+	var findings []rawFinding
+
+	for _, match := range results.GrypeResults.Matches {
+		aliases := make([]string, 0, len(match.Vulnerability.RelatedVulnerabilities))
+		for _, rel := range match.Vulnerability.RelatedVulnerabilities {
+			aliases = append(aliases, rel.ID)
+		}
+		findings = append(findings, rawFinding{
+			id:       match.Vulnerability.ID,
+			aliases:  aliases,
+			source:   "grype",
+			severity: match.Vulnerability.Severity,
+			pkg:      match.Artifact.Name,
+			version:  match.Artifact.Version,
+		})
+	}
+
+	for _, vuln := range results.SnykResults.Vulnerabilities {
+		aliases := append([]string{}, vuln.Identifiers.CVE...)
+		aliases = append(aliases, vuln.Identifiers.GHSA...)
+		findings = append(findings, rawFinding{
+			id:       vuln.ID,
+			aliases:  aliases,
+			source:   "snyk",
+			severity: vuln.Severity,
+			pkg:      vuln.Package,
+			version:  vuln.Version,
+		})
+	}
+
+	for _, target := range results.TrivyResults.Results {
+		for _, vuln := range target.Vulnerabilities {
+			findings = append(findings, rawFinding{
+				id:       vuln.VulnerabilityID,
+				aliases:  vuln.VendorIDs,
+				source:   "trivy",
+				severity: vuln.Severity,
+				pkg:      vuln.PkgName,
+				version:  vuln.InstalledVersion,
+			})
+		}
+	}
+
+	return findings
+}
+
+// suppressedKey identifies a Match/rawFinding by its (vuln_id, package,
+// version) tuple, so mergeFindingsByCVE can recognize a finding already
+// suppressed by VEX during generateVulnerabilitySummary.
+func suppressedKey(id, pkg, version string) string {
+	return id + "|" + pkg + "|" + version
+}
+
+// isFindingSuppressed reports whether a finding was already suppressed by
+// VEX (not_affected/fixed) during generateVulnerabilitySummary.
+func isFindingSuppressed(suppressed []Match, finding rawFinding) bool {
+	for _, match := range suppressed {
+		if suppressedKey(match.VulnerabilityID, match.Package, match.Version) == suppressedKey(finding.id, finding.pkg, finding.version) {
+			return true
+		}
+	}
+	return false
+}
+
+// mergeFindingsByCVE normalizes every finding across Grype, Snyk and Trivy
+// through an alias resolver: it builds a graph of {scanner_id ->
+// [aliases...]}, collapses the graph into equivalence classes via
+// union-find, and reports one MergedFinding per class, keyed by the
+// preferred CVE ID (falling back to GHSA, then vendor ID). Findings already
+// suppressed by VEX are excluded so UniqueCVEs stays consistent with the
+// suppression-aware summary.
+func mergeFindingsByCVE(results *VulnerabilityResults) []MergedFinding {
+	var findings []rawFinding
+	for _, finding := range collectRawFindings(results) {
+		if isFindingSuppressed(results.SuppressedVulnerabilities, finding) {
+			continue
+		}
+		findings = append(findings, finding)
+	}
+
+	groups := groupFindingsByAlias(findings)
+
+	merged := make([]MergedFinding, 0, len(groups))
+	for _, group := range groups {
+		merged = append(merged, buildMergedFinding(group))
+	}
+
+	// buildMergedFinding's preferred CVEID doesn't always agree with the
+	// group ordering from groupFindingsByAlias, so sort once more on the
+	// final, user-visible key to keep MergedFindings reproducible.
+	sort.Slice(merged, func(i, j int) bool { return merged[i].CVEID < merged[j].CVEID })
+
+	return merged
+}
+
+// groupFindingsByAlias unions findings that share an ID/alias via
+// union-find, returning one slice of findings per equivalence class.
+func groupFindingsByAlias(findings []rawFinding) [][]rawFinding {
+	parent := map[string]string{}
+
+	var find func(string) string
+	find = func(id string) string {
+		if parent[id] == "" {
+			parent[id] = id
+		}
+		if parent[id] != id {
+			parent[id] = find(parent[id])
+		}
+		return parent[id]
+	}
+	union := func(a, b string) {
+		rootA, rootB := find(a), find(b)
+		if rootA != rootB {
+			parent[rootA] = rootB
+		}
+	}
+
+	for _, finding := range findings {
+		find(finding.id)
+		for _, alias := range finding.aliases {
+			find(alias)
+			union(finding.id, alias)
+		}
+	}
+
+	groupsByRoot := map[string][]rawFinding{}
+	for _, finding := range findings {
+		root := find(finding.id)
+		groupsByRoot[root] = append(groupsByRoot[root], finding)
+	}
+
+	groups := make([][]rawFinding, 0, len(groupsByRoot))
+	for _, group := range groupsByRoot {
+		groups = append(groups, group)
+	}
+
+	// groupsByRoot is keyed by map, so iteration order (and therefore group
+	// order) is randomized per-process; sort by each group's smallest
+	// finding ID so MergedFindings is reproducible across runs.
+	for _, group := range groups {
+		sort.Slice(group, func(i, j int) bool { return group[i].id < group[j].id })
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0].id < groups[j][0].id })
+
+	return groups
+}
+
+// buildMergedFinding collapses one equivalence class of findings into a
+// MergedFinding, preferring a CVE ID, then GHSA, then whatever vendor ID
+// the group has.
+func buildMergedFinding(group []rawFinding) MergedFinding {
+	ids := map[string]bool{}
+	sources := map[string]bool{}
+	packages := map[string]bool{}
+	maxSeverity := ""
+
+	for _, finding := range group {
+		ids[finding.id] = true
+		for _, alias := range finding.aliases {
+			ids[alias] = true
+		}
+		sources[finding.source] = true
+		packages[finding.pkg] = true
+		if severityRank(finding.severity) > severityRank(maxSeverity) {
+			maxSeverity = finding.severity
+		}
+	}
+
+	sortedIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		sortedIDs = append(sortedIDs, id)
+	}
+	sort.Strings(sortedIDs)
+
+	preferred := preferredCVEID(sortedIDs)
+
+	merged := MergedFinding{
+		CVEID:       preferred,
+		MaxSeverity: maxSeverity,
+	}
+	for _, id := range sortedIDs {
+		if id != preferred {
+			merged.Aliases = append(merged.Aliases, id)
+		}
+	}
+	for source := range sources {
+		merged.Sources = append(merged.Sources, source)
+	}
+	sort.Strings(merged.Sources)
+	for pkg := range packages {
+		merged.AffectedPackages = append(merged.AffectedPackages, pkg)
+	}
+	sort.Strings(merged.AffectedPackages)
+
+	return merged
+}
+
+// preferredCVEID picks the canonical ID for a merged finding from a sorted
+// ID list: the lexicographically first CVE ID if one is present, otherwise
+// the first GHSA ID, otherwise the first remaining vendor ID. Requiring a
+// sorted input keeps the choice deterministic across runs.
+func preferredCVEID(sortedIDs []string) string {
+	for _, id := range sortedIDs {
+		if strings.HasPrefix(id, "CVE-") {
+			return id
+		}
+	}
+	for _, id := range sortedIDs {
+		if strings.HasPrefix(id, "GHSA-") {
+			return id
+		}
+	}
+	if len(sortedIDs) > 0 {
+		return sortedIDs[0]
+	}
+	return ""
+}
+
+// severityRank orders severities so the "highest" of a set can be found
+// with a simple comparison.
+func severityRank(severity string) int {
+	switch strings.ToUpper(severity) {
+	case "CRITICAL":
+		return 4
+	case "HIGH":
+		return 3
+	case "MEDIUM":
+		return 2
+	case "LOW":
+		return 1
+	default:
+		return 0
+	}
+}
+
+// loadVEXStatements reads and merges every VEX document configured in
+// ScannerConfig.VEXDocumentPaths into a single statement list.
+func (s *SecurityScanner) loadVEXStatements() ([]VEXStatement, error) {
+	var statements []VEXStatement
+
+	for _, path := range s.config.VEXDocumentPaths {
+		data, err := os.ReadFile(path)
 		if err != nil {
-			log.Printf("Secret scan failed: %v", err)
-		} else {
-			findings.SecretsFound = secretsFound
-			if secretsFound {
-				log.Println("⚠️  Secrets detected in repository")
-			} else {
-				log.Println("✅ No secrets detected")
+			return statements, fmt.Errorf("failed to read VEX document %s: %w", path, err)
+		}
+
+		var doc VEXDocument
+		if err := json.Unmarshal(data, &doc); err != nil {
+			return statements, fmt.Errorf("failed to parse VEX document %s: %w", path, err)
+		}
+
+		statements = append(statements, doc.Statements...)
+	}
+
+	return statements, nil
+}
+
+// matchVEXStatus looks up the VEX status and justification for a
+// (vuln_id, package, version) tuple, returning "" if no statement applies.
+func matchVEXStatus(statements []VEXStatement, vulnID, pkg, version string) (status, justification string) {
+	for _, stmt := range statements {
+		if stmt.vexID() != vulnID {
+			continue
+		}
+
+		for _, product := range stmt.Products {
+			if !vexProductMatches(product, pkg, version) {
+				continue
 			}
+			return stmt.Status, stmt.Justification
 		}
 	}
-	
-	return findings, nil
+
+	return "", ""
 }
 
-// runSecretScan executes Gitleaks for secret detection
-func (s *SecurityScanner) runSecretScan() (bool, error) {
+// vexProductMatches reports whether a VEX product entry refers to the given
+// package, optionally pinned to a version via its subcomponents.
+func vexProductMatches(product VEXProduct, pkg, version string) bool {
+	idMatches := strings.Contains(product.ID, pkg)
+	purlMatches := product.Identifiers["purl"] != "" && strings.Contains(product.Identifiers["purl"], pkg)
+	if !idMatches && !purlMatches {
+		return false
+	}
+
+	if len(product.Subcomponents) == 0 {
+		return true
+	}
+
+	for _, sub := range product.Subcomponents {
+		if strings.Contains(sub.ID, version) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// runSecretScan executes Gitleaks for secret detection, caching by the
+// sha256 of the working directory's file tree so an unchanged tree skips
+// the scan on the next run.
+func (s *SecurityScanner) runSecretScan(ctx context.Context) (found bool, cached bool, err error) {
+	key, hasKey := cacheKeyForTree(s.workDir)
+	if hasKey {
+		if data, hit := loadCache("gitleaks", key); hit {
+			var cachedResult struct {
+				SecretsFound bool `json:"secrets_found"`
+			}
+			if err := json.Unmarshal(data, &cachedResult); err == nil {
+				return cachedResult.SecretsFound, true, nil
+			}
+		}
+	}
+
 	outputPath := filepath.Join(s.outputDir, "gitleaks-results.json")
-	
-	cmd := exec.Command("gitleaks", "detect", "--source", s.workDir, "--report-format", "json", "--report-path", outputPath)
-	
+	cmd := exec.CommandContext(ctx, "gitleaks", "detect", "--source", s.workDir, "--report-format", "json", "--report-path", outputPath)
+
 	output, err := cmd.CombinedOutput()
 	if err != nil {
+		if ctx.Err() != nil {
+			return false, false, fmt.Errorf("gitleaks scan canceled: %w", ctx.Err())
+		}
 		// Gitleaks returns non-zero exit code when secrets are found
-		if strings.Contains(string(output), "leaks found") {
-			return true, nil
+		if !strings.Contains(string(output), "leaks found") {
+			return false, false, fmt.Errorf("gitleaks scan failed: %w\nOutput: %s", err, string(output))
 		}
-		return false, fmt.Errorf("gitleaks scan failed: %w\nOutput: %s", err, string(output))
+		found = true
 	}
-	
-	return false, nil
+
+	if hasKey {
+		if data, marshalErr := json.Marshal(map[string]bool{"secrets_found": found}); marshalErr == nil {
+			storeCache("gitleaks", key, data)
+		}
+	}
+
+	return found, false, nil
+}
+
+// cacheDir returns the content-addressed cache root under the user's home
+// directory.
+func cacheDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+	return filepath.Join(home, ".cache", "beacon-scanner"), nil
+}
+
+// cacheKeyForFile hashes a single file's contents, used to key vuln scan
+// results off the SBOM that produced them.
+func cacheKeyForFile(path string) (string, bool) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), true
+}
+
+// cacheKeyForTree hashes every file under root into a single digest, used
+// to key secret scan results off the working directory's content.
+func cacheKeyForTree(root string) (string, bool) {
+	var hashes []string
+
+	err := filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
+		if err != nil || info.IsDir() {
+			return nil
+		}
+		data, readErr := os.ReadFile(path)
+		if readErr != nil {
+			return nil
+		}
+		sum := sha256.Sum256(data)
+		hashes = append(hashes, hex.EncodeToString(sum[:]))
+		return nil
+	})
+	if err != nil || len(hashes) == 0 {
+		return "", false
+	}
+
+	sort.Strings(hashes)
+	combined := sha256.Sum256([]byte(strings.Join(hashes, "")))
+	return hex.EncodeToString(combined[:]), true
+}
+
+// loadCache reads a cached tool result, if one exists for the given key.
+func loadCache(tool, key string) ([]byte, bool) {
+	dir, err := cacheDir()
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(filepath.Join(dir, tool, key+".json"))
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// storeCache writes a tool result to the cache under the given key.
+// Failures are non-fatal: caching is a performance optimization, not a
+// correctness requirement.
+func storeCache(tool, key string, data []byte) {
+	dir, err := cacheDir()
+	if err != nil {
+		return
+	}
+	toolDir := filepath.Join(dir, tool)
+	if err := os.MkdirAll(toolDir, 0755); err != nil {
+		return
+	}
+	_ = os.WriteFile(filepath.Join(toolDir, key+".json"), data, 0644)
+}
+
+// RunRemediation generates (and, if configured, applies) fix patches for
+// every scan finding that has a known fixed-in version.
+func (s *SecurityScanner) RunRemediation(result *SecurityScanResult) (*remediation.Report, error) {
+	log.Println("Generating remediation patches...")
+
+	var deps []remediation.VulnerableDependency
+
+	for _, target := range result.VulnerabilityResults.TrivyResults.Results {
+		for _, vuln := range target.Vulnerabilities {
+			if vuln.FixedVersion == "" {
+				continue
+			}
+			manifestPath := s.resolveTrivyManifestPath(target.Target)
+			if manifestPath == "" {
+				continue
+			}
+			deps = append(deps, remediation.VulnerableDependency{
+				FindingID:    vuln.VulnerabilityID,
+				Package:      vuln.PkgName,
+				Version:      vuln.InstalledVersion,
+				FixedVersion: vuln.FixedVersion,
+				ManifestPath: manifestPath,
+			})
+		}
+	}
+
+	for _, match := range result.VulnerabilityResults.GrypeResults.Matches {
+		if len(match.Vulnerability.Fix.Versions) == 0 {
+			continue
+		}
+		manifestPath := s.resolveManifestPathForEcosystem(match.Artifact.Type)
+		if manifestPath == "" {
+			continue
+		}
+		deps = append(deps, remediation.VulnerableDependency{
+			FindingID:    match.Vulnerability.ID,
+			Package:      match.Artifact.Name,
+			Version:      match.Artifact.Version,
+			FixedVersion: match.Vulnerability.Fix.Versions[0],
+			ManifestPath: manifestPath,
+		})
+	}
+
+	remediator := remediation.NewRemediator(s.workDir)
+	report, err := remediator.GenerateReport(deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate remediation report: %w", err)
+	}
+
+	if err := remediator.SaveReport(report, s.outputDir); err != nil {
+		return nil, fmt.Errorf("failed to save remediation report: %w", err)
+	}
+
+	if s.config.ApplyRemediation {
+		if err := remediator.Apply(report); err != nil {
+			return nil, fmt.Errorf("failed to apply remediation patches: %w", err)
+		}
+	}
+
+	return report, nil
+}
+
+// resolveTrivyManifestPath prefers the manifest path Trivy already reported
+// for a finding (target.Target, e.g. "go.mod" or "package-lock.json")
+// instead of guessing from the package name, since Trivy scans each
+// manifest it finds independently.
+func (s *SecurityScanner) resolveTrivyManifestPath(target string) string {
+	if target == "" {
+		return ""
+	}
+	path := target
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(s.workDir, target)
+	}
+	if _, err := os.Stat(path); err != nil {
+		return ""
+	}
+	return path
+}
+
+// resolveManifestPathForEcosystem maps a Grype artifact's package type
+// (e.g. "npm", "go-module", "python") to the manifest file that ecosystem
+// pins dependencies in, falling back to a directory probe only when the
+// ecosystem is unrecognized.
+func (s *SecurityScanner) resolveManifestPathForEcosystem(pkgType string) string {
+	var candidates []string
+	switch pkgType {
+	case "npm":
+		candidates = []string{"package.json"}
+	case "go-module":
+		candidates = []string{"go.mod"}
+	case "python":
+		candidates = []string{"requirements.txt"}
+	case "deb", "rpm", "apk":
+		candidates = []string{"Dockerfile"}
+	default:
+		candidates = []string{"package.json", "go.mod", "requirements.txt", "Dockerfile"}
+	}
+
+	for _, name := range candidates {
+		path := filepath.Join(s.workDir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
 }
 
-// generateVulnerabilitySummary creates aggregated vulnerability statistics
-func (s *SecurityScanner) generateVulnerabilitySummary(results *VulnerabilityResults) VulnSummary {
+// generateVulnerabilitySummary creates aggregated vulnerability statistics,
+// suppressing findings that a VEX statement marks not_affected or fixed.
+func (s *SecurityScanner) generateVulnerabilitySummary(results *VulnerabilityResults, vexStatements []VEXStatement) VulnSummary {
 	summary := VulnSummary{}
-	
-	// Aggregate Grype results
-	for _, match := range results.GrypeResults.Matches {
+
+	// Aggregate Grype, Snyk and Trivy results alike
+	for _, finding := range collectRawFindings(results) {
+		status, justification := matchVEXStatus(vexStatements, finding.id, finding.pkg, finding.version)
+
+		if status == VEXStatusNotAffected || status == VEXStatusFixed {
+			results.SuppressedVulnerabilities = append(results.SuppressedVulnerabilities, Match{
+				VulnerabilityID: finding.id,
+				Package:         finding.pkg,
+				Version:         finding.version,
+				Severity:        finding.severity,
+				Source:          finding.source,
+				Status:          status,
+				Justification:   justification,
+			})
+			if status == VEXStatusNotAffected {
+				summary.NotAffected++
+			} else {
+				summary.Fixed++
+			}
+			continue
+		}
+
+		switch status {
+		case VEXStatusUnderInvestigation:
+			summary.UnderInvestigation++
+		default:
+			summary.Affected++
+		}
+
 		summary.TotalVulnerabilities++
-		switch strings.ToUpper(match.Vulnerability.Severity) {
+		switch strings.ToUpper(finding.severity) {
 		case "CRITICAL":
 			summary.Critical++
 		case "HIGH":
@@ -427,12 +1467,12 @@ func (s *SecurityScanner) generateVulnerabilitySummary(results *VulnerabilityRes
 			summary.Info++
 		}
 	}
-	
+
 	// Determine if deployment should be blocked
 	if s.config.BlockOnHigh {
 		summary.BlockDeployment = summary.Critical > 0 || summary.High > 0
 	}
-	
+
 	return summary
 }
 
@@ -446,7 +1486,11 @@ func (s *SecurityScanner) assessCompliance(result *SecurityScanResult) Complianc
 		if !result.SecurityFindings.SecretsFound && result.VulnerabilityResults.Summary.Critical == 0 {
 			status.SLSALevel = 2 // Tamper resistance
 		}
+		if s.provenanceSigned && result.VulnerabilityResults.Summary.Critical == 0 {
+			status.SLSALevel = 3 // Signed provenance
+		}
 	}
+	status.ProvenanceSigned = s.provenanceSigned
 	
 	// SSDF compliance (basic check)
 	status.SSDFCompliant = result.SBOMGenerated && 
@@ -454,9 +1498,14 @@ func (s *SecurityScanner) assessCompliance(result *SecurityScanResult) Complianc
 		result.VulnerabilityResults.Summary.Critical == 0
 	
 	// CIS compliance (basic check)
-	status.CISCompliant = result.SBOMGenerated && 
+	status.CISCompliant = result.SBOMGenerated &&
 		len(result.SecurityFindings.StaticAnalysis) == 0
-	
+
+	// Kubernetes hardening only applies to cluster scans
+	if s.target.Kind == ScanTargetCluster {
+		status.KubernetesHardened = s.assessKubernetesHardening()
+	}
+
 	return status
 }
 
@@ -483,7 +1532,11 @@ func (s *SecurityScanner) generateRecommendations(result *SecurityScanResult) []
 	if result.ComplianceStatus.SLSALevel < 2 {
 		recommendations = append(recommendations, "Improve SLSA compliance by implementing signed provenance")
 	}
-	
+
+	if result.RemediationReport != nil && result.RemediationReport.FixableCount > 0 {
+		recommendations = append(recommendations, fmt.Sprintf("Run with --apply to patch %d auto-fixable finding(s)", result.RemediationReport.FixableCount))
+	}
+
 	return recommendations
 }
 
@@ -529,10 +1582,14 @@ func (s *SecurityScanner) generateTextSummary(result *SecurityScanResult) string
 	vulnSummary := result.VulnerabilityResults.Summary
 	summary.WriteString(fmt.Sprintf("Vulnerability Summary:\n"))
 	summary.WriteString(fmt.Sprintf("  Total: %d\n", vulnSummary.TotalVulnerabilities))
+	if vulnSummary.UniqueCVEs > 0 {
+		summary.WriteString(fmt.Sprintf("  Unique CVEs (cross-scanner dedup): %d\n", vulnSummary.UniqueCVEs))
+	}
 	summary.WriteString(fmt.Sprintf("  Critical: %d\n", vulnSummary.Critical))
 	summary.WriteString(fmt.Sprintf("  High: %d\n", vulnSummary.High))
 	summary.WriteString(fmt.Sprintf("  Medium: %d\n", vulnSummary.Medium))
 	summary.WriteString(fmt.Sprintf("  Low: %d\n", vulnSummary.Low))
+	summary.WriteString(fmt.Sprintf("  Suppressed (VEX not_affected/fixed): %d\n", len(result.VulnerabilityResults.SuppressedVulnerabilities)))
 	summary.WriteString(fmt.Sprintf("  Block Deployment: %v\n\n", vulnSummary.BlockDeployment))
 	
 	// Security Findings
@@ -544,9 +1601,21 @@ func (s *SecurityScanner) generateTextSummary(result *SecurityScanResult) string
 	// Compliance Status
 	summary.WriteString(fmt.Sprintf("Compliance Status:\n"))
 	summary.WriteString(fmt.Sprintf("  SLSA Level: %d\n", result.ComplianceStatus.SLSALevel))
+	summary.WriteString(fmt.Sprintf("  Provenance Signed: %v\n", result.ComplianceStatus.ProvenanceSigned))
 	summary.WriteString(fmt.Sprintf("  SSDF Compliant: %v\n", result.ComplianceStatus.SSDFCompliant))
-	summary.WriteString(fmt.Sprintf("  CIS Compliant: %v\n\n", result.ComplianceStatus.CISCompliant))
-	
+	summary.WriteString(fmt.Sprintf("  CIS Compliant: %v\n", result.ComplianceStatus.CISCompliant))
+	if result.ComplianceStatus.KubernetesHardened {
+		summary.WriteString(fmt.Sprintf("  Kubernetes Hardened (CIS Benchmark): %v\n", result.ComplianceStatus.KubernetesHardened))
+	}
+	summary.WriteString("\n")
+
+	// Remediation
+	if result.RemediationReport != nil {
+		summary.WriteString(fmt.Sprintf("Remediation:\n"))
+		summary.WriteString(fmt.Sprintf("  Fixable: %d\n", result.RemediationReport.FixableCount))
+		summary.WriteString(fmt.Sprintf("  Unfixable: %d\n\n", result.RemediationReport.UnfixableCount))
+	}
+
 	// Recommendations
 	if len(result.Recommendations) > 0 {
 		summary.WriteString(fmt.Sprintf("Recommendations:\n"))
@@ -560,34 +1629,66 @@ func (s *SecurityScanner) generateTextSummary(result *SecurityScanResult) string
 
 // main function for CLI usage
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: security-scanner <work-directory> [output-directory]")
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if len(os.Args) > 1 && os.Args[1] == "kubernetes" {
+		runKubernetesSubcommand(ctx, os.Args[2:])
+		return
+	}
+
+	// Separate flags (--fix, --apply) from positional arguments
+	var positional []string
+	enableFix, applyFix, byCVE := false, false, false
+	for _, arg := range os.Args[1:] {
+		switch arg {
+		case "--fix":
+			enableFix = true
+		case "--apply":
+			applyFix = true
+		case "--by-cve":
+			byCVE = true
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) < 1 {
+		fmt.Println("Usage: security-scanner <work-directory> [output-directory] [--fix] [--apply] [--by-cve]")
+		fmt.Println("       security-scanner kubernetes [--kubeconfig path] [--context name] [--namespace ns] [output-directory]")
 		fmt.Println("Environment variables:")
 		fmt.Println("  GITHUB_REPOSITORY - Repository name (owner/repo)")
 		fmt.Println("  GITHUB_SHA - Commit SHA")
 		fmt.Println("  SNYK_TOKEN - Snyk API token (optional)")
+		fmt.Println("Flags:")
+		fmt.Println("  --fix     Generate remediation-report.json for fixable findings")
+		fmt.Println("  --apply   With --fix, write the generated patches back to manifest files")
+		fmt.Println("  --by-cve  Collapse cross-scanner duplicate findings into one entry per CVE")
 		os.Exit(1)
 	}
-	
-	workDir := os.Args[1]
+
+	workDir := positional[0]
 	outputDir := "./security-results"
-	if len(os.Args) > 2 {
-		outputDir = os.Args[2]
+	if len(positional) > 1 {
+		outputDir = positional[1]
 	}
-	
+
 	repository := os.Getenv("GITHUB_REPOSITORY")
 	if repository == "" {
 		repository = "unknown/repository"
 	}
-	
+
 	commitSHA := os.Getenv("GITHUB_SHA")
 	if commitSHA == "" {
 		commitSHA = "unknown"
 	}
-	
+
 	scanner := NewSecurityScanner(workDir, outputDir, repository, commitSHA)
-	
-	result, err := scanner.RunComprehensiveScan()
+	scanner.config.EnableRemediation = enableFix
+	scanner.config.ApplyRemediation = applyFix
+	scanner.config.ByCVE = byCVE
+
+	result, err := scanner.RunComprehensiveScan(ctx)
 	if err != nil {
 		log.Fatalf("Security scan failed: %v", err)
 	}
@@ -602,4 +1703,65 @@ func main() {
 	}
 	
 	fmt.Println("\n✅ Security scan completed successfully")
+}
+
+// runKubernetesSubcommand handles `security-scanner kubernetes --kubeconfig
+// ... --context ... --namespace ... [output-directory]`, scanning a live
+// cluster instead of a working directory.
+func runKubernetesSubcommand(ctx context.Context, args []string) {
+	var kubeconfig, kubeContext, namespace string
+	var positional []string
+
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--kubeconfig":
+			i++
+			if i < len(args) {
+				kubeconfig = args[i]
+			}
+		case "--context":
+			i++
+			if i < len(args) {
+				kubeContext = args[i]
+			}
+		case "--namespace":
+			i++
+			if i < len(args) {
+				namespace = args[i]
+			}
+		default:
+			positional = append(positional, args[i])
+		}
+	}
+
+	outputDir := "./security-results"
+	if len(positional) > 0 {
+		outputDir = positional[0]
+	}
+
+	repository := os.Getenv("GITHUB_REPOSITORY")
+	if repository == "" {
+		repository = "unknown/repository"
+	}
+
+	commitSHA := os.Getenv("GITHUB_SHA")
+	if commitSHA == "" {
+		commitSHA = "unknown"
+	}
+
+	scanner := NewKubernetesSecurityScanner(kubeconfig, kubeContext, namespace, outputDir, repository, commitSHA)
+
+	result, err := scanner.RunComprehensiveScan(ctx)
+	if err != nil {
+		log.Fatalf("Kubernetes security scan failed: %v", err)
+	}
+
+	fmt.Println(scanner.generateTextSummary(result))
+
+	if result.VulnerabilityResults.Summary.BlockDeployment {
+		fmt.Println("\n❌ DEPLOYMENT BLOCKED: Critical or high-severity vulnerabilities detected")
+		os.Exit(1)
+	}
+
+	fmt.Println("\n✅ Kubernetes security scan completed successfully")
 }
\ No newline at end of file
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCacheKeyForFileIsStableAndChangesWithContent(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sbom.json")
+	if err := os.WriteFile(path, []byte(`{"a":1}`), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	key1, ok := cacheKeyForFile(path)
+	if !ok {
+		t.Fatal("cacheKeyForFile returned ok=false for an existing file")
+	}
+	key2, _ := cacheKeyForFile(path)
+	if key1 != key2 {
+		t.Errorf("cacheKeyForFile is not stable across calls: %q != %q", key1, key2)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"a":2}`), 0644); err != nil {
+		t.Fatalf("failed to rewrite file: %v", err)
+	}
+	key3, _ := cacheKeyForFile(path)
+	if key3 == key1 {
+		t.Error("cacheKeyForFile did not change when file contents changed")
+	}
+
+	if _, ok := cacheKeyForFile(filepath.Join(dir, "missing.json")); ok {
+		t.Error("cacheKeyForFile returned ok=true for a missing file")
+	}
+}
+
+func TestCacheKeyForTreeChangesWhenAnyFileChanges(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/x\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	key1, ok := cacheKeyForTree(dir)
+	if !ok {
+		t.Fatal("cacheKeyForTree returned ok=false for a populated tree")
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644); err != nil {
+		t.Fatalf("failed to rewrite file: %v", err)
+	}
+	key2, _ := cacheKeyForTree(dir)
+	if key1 == key2 {
+		t.Error("cacheKeyForTree did not change when a tracked file's contents changed")
+	}
+}
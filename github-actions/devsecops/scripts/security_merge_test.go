@@ -0,0 +1,58 @@
+package main
+
+import "testing"
+
+func TestGroupFindingsByAliasUnionsSharedIDs(t *testing.T) {
+	findings := []rawFinding{
+		{id: "CVE-2021-23337", source: "grype", pkg: "lodash"},
+		{id: "GHSA-p6mc-m468-83gw", aliases: []string{"CVE-2021-23337"}, source: "snyk", pkg: "lodash"},
+		{id: "CVE-2022-24999", source: "trivy", pkg: "qs"},
+	}
+
+	groups := groupFindingsByAlias(findings)
+	if len(groups) != 2 {
+		t.Fatalf("groupFindingsByAlias returned %d groups, want 2", len(groups))
+	}
+
+	var lodashGroup, qsGroup []rawFinding
+	for _, group := range groups {
+		if group[0].pkg == "lodash" {
+			lodashGroup = group
+		} else {
+			qsGroup = group
+		}
+	}
+
+	if len(lodashGroup) != 2 {
+		t.Errorf("expected the CVE-2021-23337/GHSA alias pair to merge into one group of 2, got %d", len(lodashGroup))
+	}
+	if len(qsGroup) != 1 {
+		t.Errorf("expected the unrelated CVE-2022-24999 finding to stay in its own group, got %d", len(qsGroup))
+	}
+}
+
+func TestMergeFindingsByCVEExcludesSuppressedFindings(t *testing.T) {
+	results := &VulnerabilityResults{}
+	results.GrypeResults.Matches = []GrypeMatch{
+		{
+			Vulnerability: GrypeVulnerability{ID: "CVE-2021-23337", Severity: "HIGH"},
+			Artifact:      GrypeArtifact{Name: "lodash", Version: "4.17.20"},
+		},
+	}
+	results.TrivyResults.Results = []TrivyResult{
+		{Vulnerabilities: []TrivyVulnerability{
+			{VulnerabilityID: "CVE-2022-24999", PkgName: "qs", InstalledVersion: "6.5.2", Severity: "MEDIUM"},
+		}},
+	}
+	results.SuppressedVulnerabilities = []Match{
+		{VulnerabilityID: "CVE-2021-23337", Package: "lodash", Version: "4.17.20"},
+	}
+
+	merged := mergeFindingsByCVE(results)
+	if len(merged) != 1 {
+		t.Fatalf("mergeFindingsByCVE returned %d findings, want 1 (suppressed CVE-2021-23337 excluded)", len(merged))
+	}
+	if len(merged[0].AffectedPackages) != 1 || merged[0].AffectedPackages[0] != "qs" {
+		t.Errorf("merged finding packages = %v, want [qs]", merged[0].AffectedPackages)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import "testing"
+
+func TestVexProductMatchesRequiresPurlToNameThePackage(t *testing.T) {
+	cases := []struct {
+		name    string
+		product VEXProduct
+		pkg     string
+		version string
+		want    bool
+	}{
+		{
+			name:    "id contains package",
+			product: VEXProduct{ID: "pkg:npm/lodash@4.17.20"},
+			pkg:     "lodash",
+			want:    true,
+		},
+		{
+			name:    "purl matches package",
+			product: VEXProduct{ID: "unrelated-product", Identifiers: map[string]string{"purl": "pkg:npm/lodash@4.17.20"}},
+			pkg:     "lodash",
+			want:    true,
+		},
+		{
+			name:    "purl present but names a different package",
+			product: VEXProduct{ID: "unrelated-product", Identifiers: map[string]string{"purl": "pkg:npm/left-pad@1.3.0"}},
+			pkg:     "lodash",
+			want:    false,
+		},
+		{
+			name:    "neither id nor purl mention the package",
+			product: VEXProduct{ID: "unrelated-product"},
+			pkg:     "lodash",
+			want:    false,
+		},
+		{
+			name: "subcomponent pins a different version",
+			product: VEXProduct{
+				ID:            "pkg:npm/lodash",
+				Subcomponents: []VEXSubcomponent{{ID: "pkg:npm/lodash@4.17.20"}},
+			},
+			pkg:     "lodash",
+			version: "4.17.21",
+			want:    false,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := vexProductMatches(tc.product, tc.pkg, tc.version); got != tc.want {
+				t.Errorf("vexProductMatches(%+v, %q, %q) = %v, want %v", tc.product, tc.pkg, tc.version, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatchVEXStatusDoesNotSuppressUnrelatedPackages(t *testing.T) {
+	statements := []VEXStatement{
+		{
+			Vulnerability: VEXVulnerability{ID: "CVE-2021-23337"},
+			Status:        VEXStatusNotAffected,
+			Justification: "vulnerable code path is not reachable",
+			Products: []VEXProduct{
+				{ID: "unrelated-product", Identifiers: map[string]string{"purl": "pkg:npm/lodash@4.17.20"}},
+			},
+		},
+	}
+
+	status, _ := matchVEXStatus(statements, "CVE-2021-23337", "left-pad", "1.3.0")
+	if status != "" {
+		t.Errorf("matchVEXStatus suppressed an unrelated package: got status %q, want no match", status)
+	}
+
+	status, justification := matchVEXStatus(statements, "CVE-2021-23337", "lodash", "4.17.20")
+	if status != VEXStatusNotAffected || justification == "" {
+		t.Errorf("matchVEXStatus(lodash) = (%q, %q), want (%q, non-empty)", status, justification, VEXStatusNotAffected)
+	}
+}
@@ -0,0 +1,180 @@
+// Package attestation generates in-toto/SLSA provenance statements for
+// scan artifacts and signs them with Sigstore cosign in keyless mode, so
+// SecurityScanner can claim a higher SLSA level than unsigned output
+// allows.
+package attestation
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+)
+
+// Statement is an in-toto attestation statement whose subject is the scan
+// artifacts and whose predicate is a SLSA provenance document.
+type Statement struct {
+	Type          string     `json:"_type"`
+	PredicateType string     `json:"predicateType"`
+	Subject       []Subject  `json:"subject"`
+	Predicate     Provenance `json:"predicate"`
+}
+
+// Subject identifies one attested artifact by name and content digest.
+type Subject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is a SLSA provenance predicate describing how the subjects
+// were produced.
+type Provenance struct {
+	Builder    Builder    `json:"builder"`
+	BuildType  string     `json:"buildType"`
+	Invocation Invocation `json:"invocation"`
+	Metadata   Metadata   `json:"metadata"`
+}
+
+// Builder identifies the system that produced the artifacts.
+type Builder struct {
+	ID string `json:"id"`
+}
+
+// Invocation captures the build configuration that was run.
+type Invocation struct {
+	ConfigSource ConfigSource `json:"configSource"`
+}
+
+// ConfigSource identifies the repository and commit the build ran from.
+type ConfigSource struct {
+	URI    string `json:"uri"`
+	Digest string `json:"digest"`
+}
+
+// Metadata records build timing.
+type Metadata struct {
+	StartedOn  time.Time `json:"startedOn"`
+	FinishedOn time.Time `json:"finishedOn"`
+}
+
+const (
+	statementType  = "https://in-toto.io/Statement/v0.1"
+	predicateType  = "https://slsa.dev/provenance/v1"
+	buildTypeTrivy = "https://github.com/papaert-cloud/BeaconAgileHub-security-CI-CDpipelines/security-scanner"
+)
+
+// Attestor generates and signs provenance statements for the artifacts
+// produced by a scan, writing output alongside the rest of the scan
+// results under outputDir.
+type Attestor struct {
+	outputDir string
+}
+
+// NewAttestor creates an Attestor that writes to outputDir.
+func NewAttestor(outputDir string) *Attestor {
+	return &Attestor{outputDir: outputDir}
+}
+
+// GenerateStatement builds an in-toto Statement whose subject is the
+// sha256 of every artifact in artifactPaths, and whose predicate is a SLSA
+// provenance document for the given repository/commit and build window.
+func (a *Attestor) GenerateStatement(artifactPaths []string, repository, commitSHA string, startedOn, finishedOn time.Time) (*Statement, error) {
+	subjects := make([]Subject, 0, len(artifactPaths))
+
+	for _, path := range artifactPaths {
+		digest, err := sha256File(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash artifact %s: %w", path, err)
+		}
+
+		subjects = append(subjects, Subject{
+			Name:   filepath.Base(path),
+			Digest: map[string]string{"sha256": digest},
+		})
+	}
+
+	return &Statement{
+		Type:          statementType,
+		PredicateType: predicateType,
+		Subject:       subjects,
+		Predicate: Provenance{
+			Builder:   Builder{ID: builderID()},
+			BuildType: buildTypeTrivy,
+			Invocation: Invocation{
+				ConfigSource: ConfigSource{
+					URI:    fmt.Sprintf("https://github.com/%s", repository),
+					Digest: commitSHA,
+				},
+			},
+			Metadata: Metadata{
+				StartedOn:  startedOn,
+				FinishedOn: finishedOn,
+			},
+		},
+	}, nil
+}
+
+// builderID identifies the CI runner that produced the statement from
+// GITHUB_ACTIONS/RUNNER_NAME when set, falling back to "local".
+func builderID() string {
+	if os.Getenv("GITHUB_ACTIONS") == "true" {
+		runner := os.Getenv("RUNNER_NAME")
+		if runner == "" {
+			runner = "github-hosted"
+		}
+		return fmt.Sprintf("https://github.com/actions/runner/%s", runner)
+	}
+	return "local"
+}
+
+// Save writes the statement as provenance.intoto.jsonl under outputDir and
+// returns its path, for subsequent signing.
+func (a *Attestor) Save(statement *Statement) (string, error) {
+	data, err := json.Marshal(statement)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal provenance statement: %w", err)
+	}
+
+	path := filepath.Join(a.outputDir, "provenance.intoto.jsonl")
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write provenance statement: %w", err)
+	}
+
+	return path, nil
+}
+
+// Sign signs the statement at statementPath with Sigstore cosign in
+// keyless mode, using the OIDC token cosign fetches from
+// GITHUB_ACTIONS_ID_TOKEN_REQUEST_URL. It writes provenance.sig and
+// provenance.cert under outputDir and reports whether signing succeeded.
+func (a *Attestor) Sign(statementPath string) error {
+	sigPath := filepath.Join(a.outputDir, "provenance.sig")
+	certPath := filepath.Join(a.outputDir, "provenance.cert")
+
+	cmd := exec.Command("cosign", "sign-blob",
+		"--yes",
+		"--output-signature", sigPath,
+		"--output-certificate", certPath,
+		statementPath,
+	)
+
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("cosign keyless signing failed: %w\nOutput: %s", err, string(output))
+	}
+
+	return nil
+}
+
+// sha256File returns the lowercase hex sha256 digest of the file at path.
+func sha256File(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,174 @@
+// Package remediation generates fix patches for vulnerability findings that
+// have a known fixed-in version, so SecurityScanner can surface (and
+// optionally apply) concrete remediations instead of just reporting CVEs.
+package remediation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// VulnerableDependency is the scanner-agnostic input to remediation: a
+// single package/version pinned in a manifest file that a scanner flagged
+// as vulnerable, along with the minimum version known to fix it.
+type VulnerableDependency struct {
+	FindingID    string `json:"finding_id"`
+	Package      string `json:"package"`
+	Version      string `json:"version"`
+	FixedVersion string `json:"fixed_version"`
+	ManifestPath string `json:"manifest_path"`
+}
+
+// Finding is one entry in the remediation report: a concrete line-level fix
+// for a single vulnerable dependency.
+type Finding struct {
+	FindingID    string `json:"finding_id"`
+	File         string `json:"file"`
+	OriginalLine string `json:"original_line"`
+	FixedLine    string `json:"fixed_line"`
+	Confidence   string `json:"confidence"`
+}
+
+// Confidence levels for a generated fix.
+const (
+	ConfidenceHigh   = "high"
+	ConfidenceMedium = "medium"
+	ConfidenceLow    = "low"
+)
+
+// Report is the output of a remediation run: every dependency the
+// Remediator could patch, plus counts of what it could and couldn't fix.
+type Report struct {
+	Findings       []Finding `json:"findings"`
+	FixableCount   int       `json:"fixable_count"`
+	UnfixableCount int       `json:"unfixable_count"`
+}
+
+// Remediator generates (and optionally applies) fix patches against
+// manifest files under a working directory.
+type Remediator struct {
+	workDir string
+}
+
+// NewRemediator creates a new Remediator rooted at workDir.
+func NewRemediator(workDir string) *Remediator {
+	return &Remediator{workDir: workDir}
+}
+
+// GenerateReport produces a Report for the given vulnerable dependencies,
+// in dry-run mode: it locates a fix line for each dependency but does not
+// write anything to disk.
+func (r *Remediator) GenerateReport(deps []VulnerableDependency) (*Report, error) {
+	report := &Report{}
+
+	for _, dep := range deps {
+		if dep.FixedVersion == "" {
+			report.UnfixableCount++
+			continue
+		}
+
+		finding, err := r.fixManifestLine(dep)
+		if err != nil {
+			report.UnfixableCount++
+			continue
+		}
+
+		report.Findings = append(report.Findings, *finding)
+		report.FixableCount++
+	}
+
+	return report, nil
+}
+
+// Apply writes every patch in the report back to its manifest file,
+// replacing the original line with the fixed line.
+func (r *Remediator) Apply(report *Report) error {
+	for _, finding := range report.Findings {
+		data, err := os.ReadFile(finding.File)
+		if err != nil {
+			return fmt.Errorf("failed to read manifest %s: %w", finding.File, err)
+		}
+
+		patched := strings.Replace(string(data), finding.OriginalLine, finding.FixedLine, 1)
+		if err := os.WriteFile(finding.File, []byte(patched), 0644); err != nil {
+			return fmt.Errorf("failed to write manifest %s: %w", finding.File, err)
+		}
+	}
+
+	return nil
+}
+
+// SaveReport writes the report as remediation-report.json under outputDir.
+func (r *Remediator) SaveReport(report *Report, outputDir string) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal remediation report: %w", err)
+	}
+
+	path := filepath.Join(outputDir, "remediation-report.json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write remediation report: %w", err)
+	}
+
+	return nil
+}
+
+// fixManifestLine finds the line pinning dep.Package in its manifest and
+// returns the original/fixed line pair, choosing a matcher based on the
+// manifest's file name.
+func (r *Remediator) fixManifestLine(dep VulnerableDependency) (*Finding, error) {
+	data, err := os.ReadFile(dep.ManifestPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read manifest %s: %w", dep.ManifestPath, err)
+	}
+
+	pattern, replacement, confidence := manifestMatcher(dep)
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid matcher for %s: %w", dep.ManifestPath, err)
+	}
+
+	match := re.FindString(string(data))
+	if match == "" {
+		return nil, fmt.Errorf("no pin for %s found in %s", dep.Package, dep.ManifestPath)
+	}
+
+	return &Finding{
+		FindingID:    dep.FindingID,
+		File:         dep.ManifestPath,
+		OriginalLine: match,
+		FixedLine:    re.ReplaceAllString(match, replacement),
+		Confidence:   confidence,
+	}, nil
+}
+
+// manifestMatcher returns a regexp pattern that locates dep's current
+// version pin in its manifest, the replacement template that bumps it to
+// dep.FixedVersion, and a confidence level for the resulting patch.
+func manifestMatcher(dep VulnerableDependency) (pattern, replacement, confidence string) {
+	switch filepath.Base(dep.ManifestPath) {
+	case "package.json":
+		return fmt.Sprintf(`"%s":\s*"[^"]*%s[^"]*"`, regexp.QuoteMeta(dep.Package), regexp.QuoteMeta(dep.Version)),
+			fmt.Sprintf(`"%s": "^%s"`, dep.Package, dep.FixedVersion),
+			ConfidenceHigh
+	case "go.mod":
+		return fmt.Sprintf(`%s\s+v?%s\S*`, regexp.QuoteMeta(dep.Package), regexp.QuoteMeta(dep.Version)),
+			fmt.Sprintf("%s v%s", dep.Package, dep.FixedVersion),
+			ConfidenceHigh
+	case "requirements.txt":
+		return fmt.Sprintf(`%s==%s`, regexp.QuoteMeta(dep.Package), regexp.QuoteMeta(dep.Version)),
+			fmt.Sprintf("%s==%s", dep.Package, dep.FixedVersion),
+			ConfidenceHigh
+	case "Dockerfile":
+		return fmt.Sprintf(`FROM\s+%s:%s`, regexp.QuoteMeta(dep.Package), regexp.QuoteMeta(dep.Version)),
+			fmt.Sprintf("FROM %s:%s", dep.Package, dep.FixedVersion),
+			ConfidenceMedium
+	default:
+		return regexp.QuoteMeta(dep.Version), dep.FixedVersion, ConfidenceLow
+	}
+}
@@ -0,0 +1,96 @@
+package remediation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFixManifestLinePatchesKnownEcosystems(t *testing.T) {
+	cases := []struct {
+		name         string
+		manifestFile string
+		contents     string
+		dep          VulnerableDependency
+		wantFixed    string
+		wantConf     string
+	}{
+		{
+			name:         "package.json",
+			manifestFile: "package.json",
+			contents:     `{"dependencies": {"lodash": "4.17.20"}}`,
+			dep:          VulnerableDependency{Package: "lodash", Version: "4.17.20", FixedVersion: "4.17.21"},
+			wantFixed:    `"lodash": "^4.17.21"`,
+			wantConf:     ConfidenceHigh,
+		},
+		{
+			name:         "go.mod",
+			manifestFile: "go.mod",
+			contents:     "require example.com/pkg v1.2.3\n",
+			dep:          VulnerableDependency{Package: "example.com/pkg", Version: "1.2.3", FixedVersion: "1.2.4"},
+			wantFixed:    "example.com/pkg v1.2.4",
+			wantConf:     ConfidenceHigh,
+		},
+		{
+			name:         "requirements.txt",
+			manifestFile: "requirements.txt",
+			contents:     "flask==1.0.0\n",
+			dep:          VulnerableDependency{Package: "flask", Version: "1.0.0", FixedVersion: "1.1.0"},
+			wantFixed:    "flask==1.1.0",
+			wantConf:     ConfidenceHigh,
+		},
+		{
+			name:         "Dockerfile",
+			manifestFile: "Dockerfile",
+			contents:     "FROM alpine:3.14\n",
+			dep:          VulnerableDependency{Package: "alpine", Version: "3.14", FixedVersion: "3.15"},
+			wantFixed:    "FROM alpine:3.15",
+			wantConf:     ConfidenceMedium,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			manifestPath := filepath.Join(dir, tc.manifestFile)
+			if err := os.WriteFile(manifestPath, []byte(tc.contents), 0644); err != nil {
+				t.Fatalf("failed to write manifest: %v", err)
+			}
+
+			dep := tc.dep
+			dep.ManifestPath = manifestPath
+
+			r := NewRemediator(dir)
+			finding, err := r.fixManifestLine(dep)
+			if err != nil {
+				t.Fatalf("fixManifestLine returned error: %v", err)
+			}
+
+			if finding.FixedLine != tc.wantFixed {
+				t.Errorf("FixedLine = %q, want %q", finding.FixedLine, tc.wantFixed)
+			}
+			if finding.Confidence != tc.wantConf {
+				t.Errorf("Confidence = %q, want %q", finding.Confidence, tc.wantConf)
+			}
+		})
+	}
+}
+
+func TestFixManifestLineReturnsErrorWhenPinNotFound(t *testing.T) {
+	dir := t.TempDir()
+	manifestPath := filepath.Join(dir, "requirements.txt")
+	if err := os.WriteFile(manifestPath, []byte("django==3.0.0\n"), 0644); err != nil {
+		t.Fatalf("failed to write manifest: %v", err)
+	}
+
+	r := NewRemediator(dir)
+	_, err := r.fixManifestLine(VulnerableDependency{
+		Package:      "flask",
+		Version:      "1.0.0",
+		FixedVersion: "1.1.0",
+		ManifestPath: manifestPath,
+	})
+	if err == nil {
+		t.Fatal("expected an error when the dependency pin is not present in the manifest, got nil")
+	}
+}